@@ -0,0 +1,18 @@
+//go:build !dev
+
+// Package assets exposes the service's static assets (question-UI JSON
+// schemas, the default repo.json) as an fs.FS, embedded into the binary
+// for normal builds and served straight off disk when built with the
+// "dev" tag for local iteration.
+package assets
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed schemas repo.json
+var embedded embed.FS
+
+// FS is the default asset filesystem, embedded into the binary.
+var FS fs.FS = embedded
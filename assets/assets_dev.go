@@ -0,0 +1,12 @@
+//go:build dev
+
+package assets
+
+import (
+	"io/fs"
+	"os"
+)
+
+// FS serves assets straight off disk in dev builds, so local schema or
+// repo.json edits show up without a rebuild.
+var FS fs.FS = os.DirFS("assets")
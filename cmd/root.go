@@ -3,9 +3,11 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,9 +15,11 @@ import (
 	"github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/rancher/catalog-service/assets"
 	"github.com/rancher/catalog-service/manager"
-	"github.com/rancher/catalog-service/model"
+	"github.com/rancher/catalog-service/manager/migrations"
 	"github.com/rancher/catalog-service/service"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -28,7 +32,9 @@ var (
 	configFile      string
 	validateOnly    bool
 	sqlite          bool
+	postgres        bool
 	migrateDb       bool
+	assetsDir       string
 )
 
 var RootCmd = &cobra.Command{
@@ -48,7 +54,9 @@ func init() {
 	RootCmd.PersistentFlags().StringVar(&configFile, "config", "./repo.json", "")
 	RootCmd.PersistentFlags().BoolVar(&validateOnly, "validate", false, "")
 	RootCmd.PersistentFlags().BoolVar(&sqlite, "sqlite", false, "")
+	RootCmd.PersistentFlags().BoolVar(&postgres, "postgres", false, "")
 	RootCmd.PersistentFlags().BoolVar(&migrateDb, "migrate-db", false, "")
+	RootCmd.PersistentFlags().StringVar(&assetsDir, "assets-dir", "", "serve assets from this directory instead of the binary's embedded copies")
 
 	RootCmd.PersistentFlags().String("mysql-user", "", "")
 	viper.BindPFlag("mysql_user", RootCmd.PersistentFlags().Lookup("mysql-user"))
@@ -64,6 +72,21 @@ func init() {
 
 	RootCmd.PersistentFlags().String("mysql-params", "", "")
 	viper.BindPFlag("mysql_params", RootCmd.PersistentFlags().Lookup("mysql-params"))
+
+	RootCmd.PersistentFlags().String("postgres-user", "", "")
+	viper.BindPFlag("postgres_user", RootCmd.PersistentFlags().Lookup("postgres-user"))
+
+	RootCmd.PersistentFlags().String("postgres-password", "", "")
+	viper.BindPFlag("postgres_password", RootCmd.PersistentFlags().Lookup("postgres-password"))
+
+	RootCmd.PersistentFlags().String("postgres-address", "", "")
+	viper.BindPFlag("postgres_address", RootCmd.PersistentFlags().Lookup("postgres-address"))
+
+	RootCmd.PersistentFlags().String("postgres-dbname", "", "")
+	viper.BindPFlag("postgres_dbname", RootCmd.PersistentFlags().Lookup("postgres-dbname"))
+
+	RootCmd.PersistentFlags().String("postgres-params", "", "")
+	viper.BindPFlag("postgres_params", RootCmd.PersistentFlags().Lookup("postgres-params"))
 }
 
 func run(cmd *cobra.Command, args []string) {
@@ -80,6 +103,17 @@ func run(cmd *cobra.Command, args []string) {
 		}
 		db.Exec("PRAGMA foreign_keys = ON")
 		migrateDb = true
+	} else if postgres {
+		user := viper.GetString("postgres_user")
+		password := viper.GetString("postgres_password")
+		address := viper.GetString("postgres_address")
+		dbname := viper.GetString("postgres_dbname")
+		params := viper.GetString("postgres_params")
+
+		db, err = gorm.Open("postgres", formatPostgresDSN(user, password, address, dbname, params))
+		if err != nil {
+			log.Fatal(err)
+		}
 	} else {
 		user := viper.GetString("mysql_user")
 		password := viper.GetString("mysql_password")
@@ -105,24 +139,38 @@ func run(cmd *cobra.Command, args []string) {
 
 	if migrateDb {
 		log.Info("Migrating DB")
-		db.AutoMigrate(&model.CatalogModel{})
-		db.AutoMigrate(&model.TemplateModel{})
-		db.AutoMigrate(&model.VersionModel{})
-		db.AutoMigrate(&model.FileModel{})
+		if err := migrations.ApplyMigrations(db); err != nil {
+			log.Fatal(err)
+		}
+	} else if err := migrations.CheckVersion(db); err != nil {
+		log.Fatal(err)
 	}
 
 	m := manager.NewManager(cacheRoot, config, db)
-	go refresh(m, refreshInterval, validateOnly)
+
+	wrapper := &service.MuxWrapper{
+		IsReady: false,
+		DB:      db,
+		Router:  service.NewRouter(m, db, assetFS()),
+	}
+
+	go refresh(m, wrapper, refreshInterval, validateOnly)
 	if validateOnly {
 		select {}
 	}
 
 	log.Infof("Starting Catalog Service on port %d", port)
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", port), wrapper))
+}
 
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", port), &service.MuxWrapper{
-		IsReady: false,
-		Router:  service.NewRouter(manager.NewManager(cacheRoot, config, db), db),
-	}))
+// assetFS returns the filesystem the service should serve bundled assets
+// (question-UI JSON schemas, the default repo.json) from: the directory
+// named by --assets-dir when set, otherwise the binary's embedded copies.
+func assetFS() fs.FS {
+	if assetsDir != "" {
+		return os.DirFS(assetsDir)
+	}
+	return assets.FS
 }
 
 func formatDSN(user, password, address, dbname, params string) string {
@@ -145,8 +193,75 @@ func formatDSN(user, password, address, dbname, params string) string {
 	return mysqlConfig.FormatDSN()
 }
 
+// formatPostgresDSN builds a "key=value" libpq connection string, the
+// format gorm's postgres dialect expects. params is the same
+// "&"-separated "key=value" list accepted by --mysql-params, letting
+// operators pass through e.g. sslmode=disable the same way they already
+// do for MySQL. Every value is single-quoted per libpq conninfo rules so
+// a password or dbname containing a space, quote, or "=" can't break the
+// DSN or inject an extra parameter.
+func formatPostgresDSN(user, password, address, dbname, params string) string {
+	paramsMap := map[string]string{
+		"sslmode": "disable",
+	}
+	for _, param := range strings.Split(params, "&") {
+		split := strings.SplitN(param, "=", 2)
+		if len(split) > 1 {
+			paramsMap[split[0]] = split[1]
+		}
+	}
+
+	host, port := address, ""
+	if idx := strings.LastIndex(address, ":"); idx != -1 {
+		host, port = address[:idx], address[idx+1:]
+	}
+
+	paramsMap["host"] = host
+	paramsMap["dbname"] = dbname
+	paramsMap["user"] = user
+	paramsMap["password"] = password
+	if port != "" {
+		paramsMap["port"] = port
+	}
+
+	keys := make([]string, 0, len(paramsMap))
+	for k := range paramsMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+quoteLibpqValue(paramsMap[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteLibpqValue single-quotes s for use as a libpq conninfo value,
+// backslash-escaping any single quotes or backslashes it contains.
+func quoteLibpqValue(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		if r == '\\' || r == '\'' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// readConfig loads the catalogs config, preferring configFile on disk but
+// falling back to the repo.json in assetFS() (the binary's embedded copy,
+// or the one under --assets-dir when that is set) when it's missing, so
+// the service is still usable as a single static binary with no config
+// volume mounted.
 func readConfig(configFile string) (map[string]manager.CatalogConfig, error) {
 	configContents, err := ioutil.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		configContents, err = fs.ReadFile(assetFS(), "repo.json")
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -155,21 +270,33 @@ func readConfig(configFile string) (map[string]manager.CatalogConfig, error) {
 	if err = json.Unmarshal(configContents, &config); err != nil {
 		return nil, err
 	}
-	return config["catalogs"], nil
+
+	catalogs := config["catalogs"]
+	for name, catalogConfig := range catalogs {
+		if !manager.ValidKind(catalogConfig.Kind) {
+			return nil, fmt.Errorf("catalog %q: unrecognized kind %q", name, catalogConfig.Kind)
+		}
+	}
+	return catalogs, nil
 }
 
-func refresh(m *manager.Manager, refreshInterval int, validateOnly bool) {
+func refresh(m *manager.Manager, wrapper *service.MuxWrapper, refreshInterval int, validateOnly bool) {
 	if err := m.CreateConfigCatalogs(); err != nil {
 		log.Fatalf("Failed to create catalogs from config file: %v", err)
 	}
 	if err := m.RefreshAll(); err != nil {
 		log.Fatalf("Failed to do initial refresh of catalogs: %v", err)
 	}
+	wrapper.SetReady(true)
+
 	if validateOnly {
 		os.Exit(0)
 	}
 	for range time.Tick(time.Duration(refreshInterval) * time.Second) {
-		// TODO: don't want to have refresh running twice at the same time
-		go m.RefreshAll()
+		go func() {
+			if err := m.RefreshAll(); err != nil && err != manager.ErrRefreshInProgress {
+				log.Errorf("Failed to refresh catalogs: %v", err)
+			}
+		}()
 	}
 }
\ No newline at end of file
@@ -0,0 +1,49 @@
+package cmd
+
+import "testing"
+
+func TestQuoteLibpqValue(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"", "''"},
+		{"simple", "'simple'"},
+		{"has space", "'has space'"},
+		{"x sslmode=disable", "'x sslmode=disable'"},
+		{`o'brien`, `'o\'brien'`},
+		{`back\slash`, `'back\\slash'`},
+	}
+
+	for _, c := range cases {
+		if got := quoteLibpqValue(c.in); got != c.want {
+			t.Errorf("quoteLibpqValue(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestFormatPostgresDSNEscapesSpecialCharacters(t *testing.T) {
+	dsn := formatPostgresDSN("user", "x sslmode=disable", "db.example.com:5432", "catalog", "")
+
+	// A naively unquoted password would let "sslmode=disable" be parsed
+	// as its own key=value pair; quoted, it must stay inside one value.
+	if !containsKV(dsn, "password", "'x sslmode=disable'") {
+		t.Errorf("formatPostgresDSN(...) = %q, password value not safely quoted", dsn)
+	}
+	if !containsKV(dsn, "sslmode", "'disable'") {
+		t.Errorf("formatPostgresDSN(...) = %q, expected default sslmode=disable untouched", dsn)
+	}
+	if !containsKV(dsn, "host", "'db.example.com'") || !containsKV(dsn, "port", "'5432'") {
+		t.Errorf("formatPostgresDSN(...) = %q, host/port not split out of address", dsn)
+	}
+}
+
+func containsKV(dsn, key, quotedValue string) bool {
+	want := key + "=" + quotedValue
+	for i := 0; i+len(want) <= len(dsn); i++ {
+		if dsn[i:i+len(want)] == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package manager
+
+import "github.com/rancher/catalog-service/model"
+
+// CreateConfigCatalogs ensures a CatalogModel row exists for every entry
+// declared in the config file, creating any that are missing so the next
+// RefreshAll picks them up.
+func (m *Manager) CreateConfigCatalogs() error {
+	for name, config := range m.config {
+		var catalog model.CatalogModel
+		if err := m.db.Where("name = ?", name).First(&catalog).Error; err == nil {
+			continue
+		}
+		catalog.Name = name
+		catalog.URL = config.URL
+		catalog.Branch = config.Branch
+		if err := m.db.Create(&catalog).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// chartYAML is the subset of a Helm chart's Chart.yaml this package cares
+// about.
+type chartYAML struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+}
+
+// materializeChart extracts the packaged chart archive at archivePath and
+// lays it out the way syncCatalog/syncTemplate expect: cacheRoot/chart/
+// holding a template-level config.yml, and cacheRoot/chart/revision/
+// holding a version-level config.yml plus the chart's own files. Both
+// helmRepoSource and ociSource produce packaged .tgz charts, so they
+// share this instead of each re-implementing the layout.
+func materializeChart(cacheRoot, chart string, revision int, archivePath string) error {
+	scratch, err := ioutil.TempDir("", "catalog-chart-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(scratch)
+
+	if err := extractTarGz(archivePath, scratch); err != nil {
+		return fmt.Errorf("extracting %s: %v", archivePath, err)
+	}
+
+	chartDir, err := singleChartDir(scratch)
+	if err != nil {
+		return err
+	}
+
+	var meta chartYAML
+	if data, err := ioutil.ReadFile(filepath.Join(chartDir, "Chart.yaml")); err == nil {
+		yaml.Unmarshal(data, &meta)
+	}
+
+	templateDir := filepath.Join(cacheRoot, chart)
+	if err := os.MkdirAll(templateDir, 0755); err != nil {
+		return err
+	}
+	templateCfg, err := yaml.Marshal(&templateConfig{Name: meta.Name, Description: meta.Description})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(templateDir, "config.yml"), templateCfg, 0644); err != nil {
+		return err
+	}
+
+	revisionDir := filepath.Join(templateDir, strconv.Itoa(revision))
+	if err := os.MkdirAll(revisionDir, 0755); err != nil {
+		return err
+	}
+	versionCfg, err := yaml.Marshal(&versionConfig{Version: meta.Version})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(revisionDir, "config.yml"), versionCfg, 0644); err != nil {
+		return err
+	}
+
+	return copyDir(chartDir, revisionDir)
+}
+
+// singleChartDir returns the directory holding Chart.yaml under scratch:
+// either scratch itself, or, if scratch contains exactly one entry (the
+// common "archive wraps everything in <chart>/" layout produced by "helm
+// package"), that entry.
+func singleChartDir(scratch string) (string, error) {
+	if _, err := os.Stat(filepath.Join(scratch, "Chart.yaml")); err == nil {
+		return scratch, nil
+	}
+
+	entries, err := ioutil.ReadDir(scratch)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(scratch, entries[0].Name()), nil
+	}
+	return "", fmt.Errorf("manager: could not locate Chart.yaml under extracted archive")
+}
+
+// copyDir copies every regular file directly inside src into dest,
+// matching syncFiles' own non-recursive read of a version directory.
+func copyDir(src, dest string) error {
+	entries, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dest, entry.Name()), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package manager
+
+// CatalogConfig is one entry of the "catalogs" map in repo.json (or the
+// equivalent environment-provided config), describing where a catalog's
+// templates come from and how it should be fetched.
+type CatalogConfig struct {
+	URL    string `json:"url"`
+	Branch string `json:"branch"`
+
+	// Kind selects the CatalogSource implementation used to fetch this
+	// catalog. Empty defaults to KindGit, so existing repo.json files
+	// written before this field existed keep working unmodified.
+	Kind string `json:"kind"`
+}
+
+func (c CatalogConfig) sourceKind() string {
+	if c.Kind == "" {
+		return KindGit
+	}
+	return c.Kind
+}
@@ -0,0 +1,57 @@
+// Package manager owns the lifecycle of configured catalogs: fetching
+// each catalog's upstream source into a local cache and syncing the
+// templates found there into the database.
+package manager
+
+import (
+	"sync"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Manager coordinates refreshing every configured catalog against db. A
+// single Manager is meant to be shared by every caller that can trigger
+// a refresh (the background ticker, the HTTP refresh-trigger handler)
+// so they all contend on the same locks instead of racing each other.
+type Manager struct {
+	cacheRoot string
+	config    map[string]CatalogConfig
+	db        *gorm.DB
+
+	// refreshSem bounds RefreshAll to a single in-flight run: a tick
+	// that fires while a previous refresh is still running is skipped
+	// instead of stacking another goroutine on top of it.
+	refreshSem chan struct{}
+
+	// catalogLocks holds one single-slot semaphore per catalog name, so
+	// a RefreshAll pass and an on-demand RefreshCatalog for the same
+	// catalog can never run concurrently against the same cache
+	// checkout, regardless of which path triggered them.
+	catalogLocksMu sync.Mutex
+	catalogLocks   map[string]chan struct{}
+}
+
+// NewManager returns a Manager that caches fetched catalog sources under
+// cacheRoot and syncs parsed results into db.
+func NewManager(cacheRoot string, config map[string]CatalogConfig, db *gorm.DB) *Manager {
+	return &Manager{
+		cacheRoot:    cacheRoot,
+		config:       config,
+		db:           db,
+		refreshSem:   make(chan struct{}, 1),
+		catalogLocks: make(map[string]chan struct{}),
+	}
+}
+
+// catalogLock returns the single-slot semaphore guarding refreshes of
+// name, creating it on first use.
+func (m *Manager) catalogLock(name string) chan struct{} {
+	m.catalogLocksMu.Lock()
+	defer m.catalogLocksMu.Unlock()
+	lock, ok := m.catalogLocks[name]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		m.catalogLocks[name] = lock
+	}
+	return lock
+}
@@ -0,0 +1,24 @@
+package manager
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	refreshDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "catalog_refresh_duration_seconds",
+		Help: "Time spent refreshing a catalog from its source.",
+	}, []string{"catalog"})
+
+	refreshErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "catalog_refresh_errors_total",
+		Help: "Count of catalog refreshes that failed.",
+	}, []string{"catalog"})
+
+	templatesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "catalog_templates_gauge",
+		Help: "Number of templates currently known for a catalog.",
+	}, []string{"catalog"})
+)
+
+func init() {
+	prometheus.MustRegister(refreshDuration, refreshErrors, templatesGauge)
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/rancher/catalog-service/model"
+)
+
+func init() {
+	register(Migration{
+		ID:   0,
+		Name: "create_tables",
+		Up: func(db *gorm.DB) error {
+			// Table creation is still delegated to gorm's AutoMigrate,
+			// which is safe for bringing a table into existence; it's
+			// only unsafe for evolving an existing one, which is what
+			// every later migration in this package is for.
+			return db.AutoMigrate(
+				&model.CatalogModel{},
+				&model.TemplateModel{},
+				&model.VersionModel{},
+				&model.FileModel{},
+			).Error
+		},
+	})
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import "github.com/jinzhu/gorm"
+
+func init() {
+	register(Migration{
+		ID:   1,
+		Name: "add_template_base_and_folder_name",
+		Up: func(db *gorm.DB) error {
+			// Base carries the orchestration base (e.g. "cattle",
+			// "kubernetes") a template applies to; FolderName lets
+			// templates be looked up by their on-disk directory
+			// independent of display Name. Both were previously left
+			// to gorm's best-effort AutoMigrate.
+			if err := db.Exec(`ALTER TABLE catalog_template ADD COLUMN base VARCHAR(255)`).Error; err != nil {
+				return err
+			}
+			return db.Exec(`ALTER TABLE catalog_template ADD COLUMN folder_name VARCHAR(255)`).Error
+		},
+	})
+}
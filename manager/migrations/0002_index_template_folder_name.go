@@ -0,0 +1,20 @@
+package migrations
+
+import "github.com/jinzhu/gorm"
+
+func init() {
+	register(Migration{
+		ID:   2,
+		Name: "index_template_folder_name",
+		Up: func(db *gorm.DB) error {
+			if err := dropIndexIfExists(db, "catalog_template", "idx_catalog_template_folder_name"); err != nil {
+				return err
+			}
+			sql, err := rawSQL(db, "0002_index_template_folder_name.sql")
+			if err != nil {
+				return err
+			}
+			return db.Exec(sql).Error
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import "github.com/jinzhu/gorm"
+
+func init() {
+	register(Migration{
+		ID:   3,
+		Name: "add_catalog_refresh_status",
+		Up: func(db *gorm.DB) error {
+			// LastRefresh/LastError/DurationMs back the per-catalog
+			// status exposed over HTTP, so operators can tell a slow
+			// refresh from a failing one without grepping logs.
+			if err := db.Exec(`ALTER TABLE catalog ADD COLUMN last_refresh TIMESTAMP NULL`).Error; err != nil {
+				return err
+			}
+			if err := db.Exec(`ALTER TABLE catalog ADD COLUMN last_error VARCHAR(1024)`).Error; err != nil {
+				return err
+			}
+			return db.Exec(`ALTER TABLE catalog ADD COLUMN duration_ms BIGINT`).Error
+		},
+	})
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// dropIndexIfExists drops index on table, tolerating the case where it
+// doesn't exist. SQLite and Postgres accept "DROP INDEX IF EXISTS"
+// directly; MySQL has no equivalent and raises error 1091
+// (ER_CANT_DROP_FIELD_OR_KEY) instead, which we swallow here so migrations
+// stay safe to author without knowing the prior schema state.
+func dropIndexIfExists(db *gorm.DB, table, index string) error {
+	switch db.Dialect().GetName() {
+	case "sqlite3", "postgres":
+		return db.Exec(fmt.Sprintf("DROP INDEX IF EXISTS %s", index)).Error
+	}
+	err := db.Exec(fmt.Sprintf("DROP INDEX %s ON %s", index, table)).Error
+	if err != nil && strings.Contains(err.Error(), "1091") {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+// Package migrations implements a versioned, idempotent schema migration
+// subsystem for the catalog service's database. Each migration is
+// registered via init() in its own file and applied in ascending ID
+// order; migrations already recorded as applied are skipped, so running
+// ApplyMigrations against an up-to-date database is a safe no-op.
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/jinzhu/gorm"
+)
+
+// Migration is a single, ordered schema change. ID must be unique and is
+// used both for ordering and as the bookkeeping key in catalog_migrations.
+type Migration struct {
+	ID   int
+	Name string
+	Up   func(db *gorm.DB) error
+}
+
+var registered []Migration
+
+// register adds a migration to the set applied by ApplyMigrations. It is
+// called from each migration file's init().
+func register(m Migration) {
+	for _, existing := range registered {
+		if existing.ID == m.ID {
+			panic(fmt.Sprintf("migrations: duplicate migration ID %d (%q and %q)", m.ID, existing.Name, m.Name))
+		}
+	}
+	registered = append(registered, m)
+}
+
+type migrationRecord struct {
+	ID        int `gorm:"primary_key"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (migrationRecord) TableName() string {
+	return "catalog_migrations"
+}
+
+// ExpectedVersion is the highest migration ID known to this binary.
+func ExpectedVersion() int {
+	max := 0
+	for _, m := range registered {
+		if m.ID > max {
+			max = m.ID
+		}
+	}
+	return max
+}
+
+// CurrentVersion returns the highest migration ID recorded as applied in db,
+// creating the bookkeeping table if it doesn't exist yet.
+func CurrentVersion(db *gorm.DB) (int, error) {
+	if err := db.AutoMigrate(&migrationRecord{}).Error; err != nil {
+		return 0, err
+	}
+	var max int
+	row := db.Table("catalog_migrations").Select("COALESCE(MAX(id), 0)").Row()
+	if err := row.Scan(&max); err != nil {
+		return 0, err
+	}
+	return max, nil
+}
+
+// ApplyMigrations runs every registered migration newer than the DB's
+// current version, in ascending order, each inside its own transaction
+// that also records the migration as applied. It fails fast on the first
+// error, leaving the DB at the last successfully applied migration.
+func ApplyMigrations(db *gorm.DB) error {
+	sort.Slice(registered, func(i, j int) bool { return registered[i].ID < registered[j].ID })
+
+	current, err := CurrentVersion(db)
+	if err != nil {
+		return fmt.Errorf("migrations: reading current version: %v", err)
+	}
+
+	for _, m := range registered {
+		if m.ID <= current {
+			continue
+		}
+		log.Infof("Applying migration %04d_%s", m.ID, m.Name)
+		tx := db.Begin()
+		if err := m.Up(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrations: applying %04d_%s: %v", m.ID, m.Name, err)
+		}
+		if err := tx.Create(&migrationRecord{ID: m.ID, Name: m.Name, AppliedAt: time.Now()}).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrations: recording %04d_%s: %v", m.ID, m.Name, err)
+		}
+		if err := tx.Commit().Error; err != nil {
+			return fmt.Errorf("migrations: committing %04d_%s: %v", m.ID, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// CheckVersion fails if the DB's recorded version doesn't match what this
+// binary expects, so the service never runs against a schema it doesn't
+// know about instead of silently relying on gorm's best-effort AutoMigrate.
+func CheckVersion(db *gorm.DB) error {
+	current, err := CurrentVersion(db)
+	if err != nil {
+		return err
+	}
+	if expected := ExpectedVersion(); current != expected {
+		return fmt.Errorf("migrations: database is at version %d, binary expects %d; restart with --migrate-db", current, expected)
+	}
+	return nil
+}
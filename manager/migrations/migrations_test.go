@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+func TestRegisterPanicsOnDuplicateID(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("register did not panic on a duplicate migration ID")
+		}
+	}()
+	register(Migration{ID: 0, Name: "duplicate", Up: func(db *gorm.DB) error { return nil }})
+}
+
+func TestApplyMigrationsSkipsAlreadyApplied(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("opening sqlite3: %v", err)
+	}
+	defer db.Close()
+
+	runs := 0
+	register(Migration{
+		ID:   9999,
+		Name: "test_only",
+		Up: func(db *gorm.DB) error {
+			runs++
+			return nil
+		},
+	})
+
+	if err := ApplyMigrations(db); err != nil {
+		t.Fatalf("first ApplyMigrations: %v", err)
+	}
+	if runs != 1 {
+		t.Fatalf("migration ran %d times on first ApplyMigrations, want 1", runs)
+	}
+
+	if err := ApplyMigrations(db); err != nil {
+		t.Fatalf("second ApplyMigrations: %v", err)
+	}
+	if runs != 1 {
+		t.Fatalf("migration ran again on second ApplyMigrations (total %d), want still 1", runs)
+	}
+
+	current, err := CurrentVersion(db)
+	if err != nil {
+		t.Fatalf("CurrentVersion: %v", err)
+	}
+	if current != 9999 {
+		t.Fatalf("CurrentVersion() = %d, want 9999", current)
+	}
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"embed"
+
+	"github.com/jinzhu/gorm"
+)
+
+//go:embed mysql/*.sql
+var mysqlSQL embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteSQL embed.FS
+
+// rawSQL returns the embedded migration SQL for name, picked for db's
+// dialect, for changes (index creation, charset changes, ...) that can't
+// be expressed portably through gorm.
+func rawSQL(db *gorm.DB, name string) (string, error) {
+	fsys, dir := sqliteSQL, "sqlite"
+	if db.Dialect().GetName() == "mysql" {
+		fsys, dir = mysqlSQL, "mysql"
+	}
+	b, err := fsys.ReadFile(dir + "/" + name)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
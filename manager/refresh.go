@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/rancher/catalog-service/model"
+)
+
+// ErrRefreshInProgress is returned by RefreshAll when a previous refresh
+// is still running; callers started on a timer should treat it as "skip
+// this tick", not a failure.
+var ErrRefreshInProgress = errors.New("manager: refresh already in progress")
+
+// RefreshAll fetches and re-syncs every catalog known to the manager. If
+// a refresh is already in progress it returns ErrRefreshInProgress
+// immediately rather than overlapping with it. The first per-catalog
+// error aborts the remaining catalogs.
+func (m *Manager) RefreshAll() error {
+	select {
+	case m.refreshSem <- struct{}{}:
+		defer func() { <-m.refreshSem }()
+	default:
+		return ErrRefreshInProgress
+	}
+
+	for name, config := range m.config {
+		if err := m.refreshCatalog(name, config); err != nil {
+			if err == ErrRefreshInProgress {
+				// An on-demand RefreshCatalog is already refreshing
+				// this one catalog; leave it be and move on to the
+				// rest instead of aborting the whole pass.
+				continue
+			}
+			return fmt.Errorf("manager: refreshing catalog %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// RefreshCatalog fetches and re-syncs a single catalog immediately,
+// bypassing the refresh interval. It shares refreshCatalog's per-catalog
+// lock with RefreshAll, so an on-demand request and a scheduled pass can
+// never run concurrently against the same catalog's cache checkout.
+func (m *Manager) RefreshCatalog(name string) error {
+	config, ok := m.config[name]
+	if !ok {
+		return fmt.Errorf("manager: unknown catalog %q", name)
+	}
+	return m.refreshCatalog(name, config)
+}
+
+func (m *Manager) refreshCatalog(name string, config CatalogConfig) error {
+	lock := m.catalogLock(name)
+	select {
+	case lock <- struct{}{}:
+		defer func() { <-lock }()
+	default:
+		return ErrRefreshInProgress
+	}
+
+	start := time.Now()
+
+	source, err := newCatalogSource(config)
+	if err != nil {
+		return m.recordRefresh(name, start, err)
+	}
+
+	cacheRoot := filepath.Join(m.cacheRoot, name)
+	syncErr := source.Fetch(cacheRoot)
+	if syncErr == nil {
+		syncErr = m.syncCatalog(name, cacheRoot)
+	}
+
+	return m.recordRefresh(name, start, syncErr)
+}
+
+// recordRefresh stamps a catalog's LastRefresh/LastError/DurationMs and
+// reports the outcome to Prometheus, returning refreshErr unchanged so
+// callers can still propagate it.
+func (m *Manager) recordRefresh(name string, start time.Time, refreshErr error) error {
+	duration := time.Since(start)
+	refreshDuration.WithLabelValues(name).Observe(duration.Seconds())
+
+	lastError := ""
+	if refreshErr != nil {
+		lastError = refreshErr.Error()
+		refreshErrors.WithLabelValues(name).Inc()
+	}
+
+	m.db.Model(&model.CatalogModel{}).Where("name = ?", name).Updates(map[string]interface{}{
+		"last_refresh": time.Now(),
+		"last_error":   lastError,
+		"duration_ms":  duration.Milliseconds(),
+	})
+
+	var templateCount int
+	m.db.Table("catalog_template").
+		Joins("JOIN catalog ON catalog.id = catalog_template.catalog_id").
+		Where("catalog.name = ?", name).
+		Count(&templateCount)
+	templatesGauge.WithLabelValues(name).Set(float64(templateCount))
+
+	return refreshErr
+}
@@ -0,0 +1,50 @@
+package manager
+
+import "fmt"
+
+// Recognized CatalogConfig.Kind values.
+const (
+	KindGit      = "git"
+	KindHelmRepo = "helm-repo"
+	KindOCI      = "oci"
+)
+
+// CatalogSource fetches a single catalog's templates into cacheRoot so
+// they can be parsed and synced into the database. Implementations are
+// selected by CatalogConfig.Kind, letting the manager refresh catalogs
+// hosted as plain git repos, Helm chart repositories, or OCI registries
+// through the same RefreshAll loop.
+type CatalogSource interface {
+	// Fetch materializes the catalog's templates under cacheRoot,
+	// overwriting any contents left by a previous fetch.
+	Fetch(cacheRoot string) error
+	// Kind identifies the source implementation, matching the
+	// CatalogConfig.Kind value that selects it.
+	Kind() string
+}
+
+// newCatalogSource returns the CatalogSource implementation for config, or
+// an error if config.Kind names something unrecognized.
+func newCatalogSource(config CatalogConfig) (CatalogSource, error) {
+	switch config.sourceKind() {
+	case KindGit:
+		return &gitSource{config: config}, nil
+	case KindHelmRepo:
+		return &helmRepoSource{config: config}, nil
+	case KindOCI:
+		return &ociSource{config: config}, nil
+	default:
+		return nil, fmt.Errorf("manager: unknown catalog kind %q", config.Kind)
+	}
+}
+
+// ValidKind reports whether kind is empty (defaulting to git) or one of
+// the recognized CatalogSource kinds.
+func ValidKind(kind string) bool {
+	switch kind {
+	case "", KindGit, KindHelmRepo, KindOCI:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// gitSource fetches a catalog from a git repository, cloning or
+// fetching+resetting config.Branch into cacheRoot. If the checkout has a
+// .gitattributes, "git lfs pull" is run afterward to materialize the real
+// contents of any LFS-tracked files (icons, screenshots, ...) referenced
+// by templates, instead of leaving pointer stubs in place.
+type gitSource struct {
+	config CatalogConfig
+}
+
+func (s *gitSource) Kind() string { return KindGit }
+
+func (s *gitSource) Fetch(cacheRoot string) error {
+	if _, err := os.Stat(filepath.Join(cacheRoot, ".git")); err == nil {
+		if err := runGit(cacheRoot, "fetch", "origin", s.config.Branch); err != nil {
+			return err
+		}
+		if err := runGit(cacheRoot, "reset", "--hard", "origin/"+s.config.Branch); err != nil {
+			return err
+		}
+	} else {
+		if err := runGit("", "clone", "--branch", s.config.Branch, s.config.URL, cacheRoot); err != nil {
+			return err
+		}
+	}
+	return s.pullLFS(cacheRoot)
+}
+
+func (s *gitSource) pullLFS(cacheRoot string) error {
+	if _, err := os.Stat(filepath.Join(cacheRoot, ".gitattributes")); os.IsNotExist(err) {
+		return nil
+	}
+	return runGit(cacheRoot, "lfs", "pull")
+}
+
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// helmRepoSource fetches a catalog from a Helm chart repository's
+// index.yaml (the format "helm repo add" consumes), downloading every
+// referenced chart archive and materializing it into the numbered
+// template/revision layout syncCatalog expects.
+type helmRepoSource struct {
+	config CatalogConfig
+}
+
+func (s *helmRepoSource) Kind() string { return KindHelmRepo }
+
+type helmRepoIndex struct {
+	Entries map[string][]struct {
+		Version string   `yaml:"version"`
+		URLs    []string `yaml:"urls"`
+	} `yaml:"entries"`
+}
+
+func (s *helmRepoSource) Fetch(cacheRoot string) error {
+	resp, err := http.Get(strings.TrimSuffix(s.config.URL, "/") + "/index.yaml")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("manager: fetching %s: unexpected status %s", s.config.URL, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var index helmRepoIndex
+	if err := yaml.Unmarshal(body, &index); err != nil {
+		return err
+	}
+
+	scratch, err := ioutil.TempDir("", "catalog-helm-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(scratch)
+
+	for chart, versions := range index.Entries {
+		sortedVersions := make([]string, 0, len(versions))
+		byVersion := make(map[string][]string, len(versions))
+		for _, v := range versions {
+			if len(v.URLs) == 0 {
+				continue
+			}
+			sortedVersions = append(sortedVersions, v.Version)
+			byVersion[v.Version] = v.URLs
+		}
+		sort.Slice(sortedVersions, func(i, j int) bool {
+			return compareSemver(sortedVersions[i], sortedVersions[j]) < 0
+		})
+
+		for revision, version := range sortedVersions {
+			archivePath := filepath.Join(scratch, chart, version+".tgz")
+			if err := downloadFile(byVersion[version][0], archivePath); err != nil {
+				return err
+			}
+			if err := materializeChart(cacheRoot, chart, revision, archivePath); err != nil {
+				return fmt.Errorf("materializing %s %s: %v", chart, version, err)
+			}
+		}
+	}
+	return nil
+}
+
+// compareSemver orders two "x.y.z"-style version strings numerically by
+// component, ignoring a leading "v" and anything after the first "-"
+// (pre-release suffixes). It falls back to a plain string compare of the
+// full versions when a component doesn't parse, or when the numeric
+// components tie, so ordering stays deterministic either way.
+func compareSemver(a, b string) int {
+	as := strings.SplitN(strings.TrimPrefix(a, "v"), "-", 2)[0]
+	bs := strings.SplitN(strings.TrimPrefix(b, "v"), "-", 2)[0]
+	aParts := strings.Split(as, ".")
+	bParts := strings.Split(bs, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var an, bn int
+		var aOk, bOk error
+		if i < len(aParts) {
+			an, aOk = atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bn, bOk = atoi(bParts[i])
+		}
+		if aOk != nil || bOk != nil {
+			return strings.Compare(a, b)
+		}
+		if an != bn {
+			return an - bn
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+func atoi(s string) (int, error) {
+	return strconv.Atoi(s)
+}
+
+func downloadFile(url, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ociSource fetches a catalog distributed as Helm charts pushed to an OCI
+// registry (the Helm-on-OCI layout), shelling out to "oras pull" to
+// download the referenced chart archives and materializing each into the
+// numbered template/revision layout syncCatalog expects.
+type ociSource struct {
+	config CatalogConfig
+}
+
+func (s *ociSource) Kind() string { return KindOCI }
+
+func (s *ociSource) Fetch(cacheRoot string) error {
+	scratch, err := ioutil.TempDir("", "catalog-oci-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(scratch)
+
+	cmd := exec.Command("oras", "pull", s.config.URL, "-o", scratch)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	archives, err := filepath.Glob(filepath.Join(scratch, "*.tgz"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(archives)
+
+	chart := ociChartName(s.config.URL)
+	for revision, archivePath := range archives {
+		if err := materializeChart(cacheRoot, chart, revision, archivePath); err != nil {
+			return fmt.Errorf("materializing %s: %v", archivePath, err)
+		}
+	}
+	return nil
+}
+
+// ociChartName derives a template folder name from an OCI reference like
+// "registry.example.com/charts/my-app:1.2.3", stripping the registry host
+// and any tag so repeated refreshes of the same chart land in the same
+// template directory regardless of which tag was last pulled.
+func ociChartName(ref string) string {
+	ref = strings.TrimPrefix(ref, "oci://")
+	if idx := strings.LastIndex(ref, "/"); idx != -1 {
+		ref = ref[idx+1:]
+	}
+	if idx := strings.LastIndex(ref, ":"); idx != -1 {
+		ref = ref[:idx]
+	}
+	return ref
+}
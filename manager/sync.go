@@ -0,0 +1,167 @@
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/catalog-service/model"
+	"gopkg.in/yaml.v2"
+)
+
+var versionDirPattern = regexp.MustCompile(`^\d+$`)
+
+type templateConfig struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Category    string `yaml:"category"`
+}
+
+type versionConfig struct {
+	Version               string `yaml:"version"`
+	MinimumRancherVersion string `yaml:"minimum_rancher_version"`
+	MaximumRancherVersion string `yaml:"maximum_rancher_version"`
+	UpgradeFrom           string `yaml:"upgrade_from"`
+}
+
+// globalEnvironmentId marks a version as visible to every environment.
+// LookupVersionModel/LookupVersions (model/version.go) only ever query
+// for a specific environment ID or this one, so every version synced
+// from a config-driven catalog has to carry it to be reachable through
+// the public API at all.
+const globalEnvironmentId = "global"
+
+// syncCatalog walks the templates fetched into cacheRoot (one directory
+// per template, one numbered subdirectory per version, as laid out by
+// every CatalogSource) and upserts them, their versions, and their files
+// into the database under the named catalog.
+func (m *Manager) syncCatalog(name, cacheRoot string) error {
+	var catalog model.CatalogModel
+	if err := m.db.Where("name = ?", name).First(&catalog).Error; err != nil {
+		return fmt.Errorf("loading catalog %q: %v", name, err)
+	}
+
+	entries, err := ioutil.ReadDir(cacheRoot)
+	if err != nil {
+		return fmt.Errorf("reading %s: %v", cacheRoot, err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if err := m.syncTemplate(&catalog, entry.Name(), filepath.Join(cacheRoot, entry.Name())); err != nil {
+			return fmt.Errorf("syncing template %q: %v", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) syncTemplate(catalog *model.CatalogModel, folderName, templateDir string) error {
+	config := readTemplateConfig(templateDir)
+
+	var template model.TemplateModel
+	m.db.Where("catalog_id = ? AND folder_name = ?", catalog.ID, folderName).FirstOrInit(&template)
+	template.CatalogId = catalog.ID
+	template.FolderName = folderName
+	template.Name = config.Name
+	if template.Name == "" {
+		template.Name = folderName
+	}
+	template.Description = config.Description
+	template.Category = config.Category
+	if err := m.db.Save(&template).Error; err != nil {
+		return err
+	}
+
+	versionDirs, err := ioutil.ReadDir(templateDir)
+	if err != nil {
+		return err
+	}
+	for _, v := range versionDirs {
+		if !v.IsDir() || !versionDirPattern.MatchString(v.Name()) {
+			continue
+		}
+		revision, _ := strconv.Atoi(v.Name())
+		if err := m.syncVersion(&template, revision, filepath.Join(templateDir, v.Name())); err != nil {
+			return fmt.Errorf("syncing version %d: %v", revision, err)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) syncVersion(template *model.TemplateModel, revision int, versionDir string) error {
+	var version model.VersionModel
+	m.db.Where("template_id = ? AND revision = ?", template.ID, revision).FirstOrInit(&version)
+	version.TemplateId = template.ID
+	version.Revision = revision
+	version.EnvironmentId = globalEnvironmentId
+
+	config := readVersionConfig(versionDir)
+	version.Version = config.Version
+	version.MinimumRancherVersion = config.MinimumRancherVersion
+	version.MaximumRancherVersion = config.MaximumRancherVersion
+	version.UpgradeFrom = config.UpgradeFrom
+
+	if readme, err := ioutil.ReadFile(filepath.Join(versionDir, "README.md")); err == nil {
+		version.Readme = string(readme)
+	}
+	if err := m.db.Save(&version).Error; err != nil {
+		return err
+	}
+
+	return m.syncFiles(&version, versionDir)
+}
+
+// syncFiles upserts one FileModel per regular file in versionDir. Files
+// are read post-CatalogSource.Fetch, so LFS-smudged content from
+// gitSource lands here as real bytes rather than pointer stubs.
+func (m *Manager) syncFiles(version *model.VersionModel, versionDir string) error {
+	entries, err := ioutil.ReadDir(versionDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		contents, err := ioutil.ReadFile(filepath.Join(versionDir, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		var file model.FileModel
+		m.db.Where("version_id = ? AND name = ?", version.ID, entry.Name()).FirstOrInit(&file)
+		file.VersionId = version.ID
+		file.Name = entry.Name()
+		file.Contents = string(contents)
+		if err := m.db.Save(&file).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readTemplateConfig(templateDir string) templateConfig {
+	var config templateConfig
+	data, err := ioutil.ReadFile(filepath.Join(templateDir, "config.yml"))
+	if err != nil {
+		return config
+	}
+	yaml.Unmarshal(data, &config)
+	return config
+}
+
+func readVersionConfig(versionDir string) versionConfig {
+	var config versionConfig
+	data, err := ioutil.ReadFile(filepath.Join(versionDir, "config.yml"))
+	if err != nil {
+		return config
+	}
+	yaml.Unmarshal(data, &config)
+	return config
+}
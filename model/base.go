@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Base is embedded by every top-level catalog record (CatalogModel,
+// TemplateModel, VersionModel, FileModel) to give it a gorm-managed
+// primary key and timestamps.
+type Base struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
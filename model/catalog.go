@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Catalog is the set of fields gorm maps onto the "catalog" table, plus
+// the LastRefresh/LastError/DurationMs status columns added by migration
+// 0003 to back the per-catalog status endpoint.
+type Catalog struct {
+	Name   string `json:"id"`
+	URL    string `json:"url"`
+	Branch string `json:"branch"`
+
+	LastRefresh time.Time `json:"lastRefresh"`
+	LastError   string    `json:"lastError"`
+	DurationMs  int64     `json:"durationMs"`
+}
+
+type CatalogModel struct {
+	Base
+	Catalog
+}
@@ -0,0 +1,15 @@
+package model
+
+// File is a single file belonging to a template version (a Kubernetes
+// manifest, a docker-compose.yml, ...), stored verbatim so it can be
+// served back out through TemplateVersionResource.Files.
+type File struct {
+	VersionId uint   `sql:"type:integer REFERENCES catalog_version(id) ON DELETE CASCADE"`
+	Name      string `json:"name"`
+	Contents  string `json:"-"`
+}
+
+type FileModel struct {
+	Base
+	File
+}
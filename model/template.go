@@ -0,0 +1,20 @@
+package model
+
+// Template is the set of fields gorm maps onto the "catalog_template"
+// table. OrchestrationBase backs the "base" column added by migration
+// 0001 (e.g. "cattle", "kubernetes"); it can't be named Base like the
+// embedded struct without shadowing it.
+type Template struct {
+	CatalogId uint `sql:"type:integer REFERENCES catalog(id) ON DELETE CASCADE"`
+
+	FolderName        string `gorm:"column:folder_name" json:"-"`
+	OrchestrationBase string `gorm:"column:base" json:"base"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	Category          string `json:"category"`
+}
+
+type TemplateModel struct {
+	Base
+	Template
+}
@@ -1,10 +1,41 @@
 package model
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/jinzhu/gorm"
 	"github.com/rancher/go-rancher/client"
 )
 
+// dialectQuery rewrites a query written with positional "?" placeholders
+// into whatever bind-variable syntax db's dialect actually expects (e.g.
+// "$1", "$2", ... for Postgres), so the same raw SQL below runs unmodified
+// against MySQL, SQLite, and Postgres.
+func dialectQuery(db *gorm.DB, query string) string {
+	return rewritePlaceholders(db.Dialect().GetName(), query)
+}
+
+// rewritePlaceholders does the actual "?" -> "$1", "$2", ... substitution
+// for dialectName == "postgres", and is a no-op otherwise. Split out from
+// dialectQuery so it can be unit tested without a live *gorm.DB.
+func rewritePlaceholders(dialectName, query string) string {
+	if dialectName != "postgres" {
+		return query
+	}
+	var b strings.Builder
+	i := 0
+	for _, r := range query {
+		if r == '?' {
+			i++
+			fmt.Fprintf(&b, "$%d", i)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 // TODO: might need a Base field for filtering
 // TODO: might need a FolderName field for filtering
 type Version struct {
@@ -41,7 +72,7 @@ type TemplateVersionResource struct {
 // TODO: needs a base filter (make sure to use a map)
 func LookupVersionModel(db *gorm.DB, environmentId, catalog, template string, revision int) *VersionModel {
 	var versionModel VersionModel
-	db.Raw(`
+	db.Raw(dialectQuery(db, `
 SELECT catalog_version.*
 FROM catalog_version, catalog_template, catalog
 WHERE (catalog_version.environment_id = ? OR catalog_version.environment_id = ?)
@@ -50,13 +81,13 @@ AND catalog_template.catalog_id = catalog.id
 AND catalog.name = ?
 AND catalog_template.folder_name = ?
 AND catalog_version.revision = ?
-`, environmentId, "global", catalog, template, revision).Scan(&versionModel)
+`), environmentId, "global", catalog, template, revision).Scan(&versionModel)
 	return &versionModel
 }
 
 func LookupVersions(db *gorm.DB, environmentId, catalog, template string) []Version {
 	var versionModels []VersionModel
-	db.Raw(`
+	db.Raw(dialectQuery(db, `
 SELECT catalog_version.*
 FROM catalog_version, catalog_template, catalog
 WHERE (catalog_version.environment_id = ? OR catalog_version.environment_id = ?)
@@ -64,7 +95,7 @@ AND catalog_version.template_id = catalog_template.id
 AND catalog_template.catalog_id = catalog.id
 AND catalog.name = ?
 AND catalog_template.folder_name = ?
-`, environmentId, "global", catalog, template).Scan(&versionModels)
+`), environmentId, "global", catalog, template).Scan(&versionModels)
 
 	var versions []Version
 	for _, versionModel := range versionModels {
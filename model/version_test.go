@@ -0,0 +1,132 @@
+package model
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+func TestRewritePlaceholders(t *testing.T) {
+	const query = `SELECT * FROM catalog WHERE name = ? AND branch = ? OR id = ?`
+
+	cases := []struct {
+		dialectName string
+		want        string
+	}{
+		{"mysql", query},
+		{"sqlite3", query},
+		{"postgres", `SELECT * FROM catalog WHERE name = $1 AND branch = $2 OR id = $3`},
+	}
+
+	for _, c := range cases {
+		if got := rewritePlaceholders(c.dialectName, query); got != c.want {
+			t.Errorf("rewritePlaceholders(%q, query) = %q, want %q", c.dialectName, got, c.want)
+		}
+	}
+}
+
+// lookupTestDBs returns one *gorm.DB per dialect to run the
+// LookupVersionModel/LookupVersions matrix against. sqlite3 is in-process
+// and always runs; mysql/postgres only run when their DSN is supplied via
+// the CATALOG_SERVICE_TEST_MYSQL_DSN/CATALOG_SERVICE_TEST_POSTGRES_DSN
+// env vars, since neither server is available in a plain `go test` run.
+func lookupTestDBs(t *testing.T) map[string]*gorm.DB {
+	t.Helper()
+
+	dbs := map[string]*gorm.DB{}
+
+	sqliteDB, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("opening sqlite3: %v", err)
+	}
+	t.Cleanup(func() { sqliteDB.Close() })
+	dbs["sqlite3"] = sqliteDB
+
+	if dsn := os.Getenv("CATALOG_SERVICE_TEST_MYSQL_DSN"); dsn != "" {
+		db, err := gorm.Open("mysql", dsn)
+		if err != nil {
+			t.Fatalf("opening mysql: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		dbs["mysql"] = db
+	}
+
+	if dsn := os.Getenv("CATALOG_SERVICE_TEST_POSTGRES_DSN"); dsn != "" {
+		db, err := gorm.Open("postgres", dsn)
+		if err != nil {
+			t.Fatalf("opening postgres: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		dbs["postgres"] = db
+	}
+
+	return dbs
+}
+
+func TestLookupVersionModelAndLookupVersions(t *testing.T) {
+	for dialectName, db := range lookupTestDBs(t) {
+		dialectName, db := dialectName, db
+		t.Run(dialectName, func(t *testing.T) {
+			db.SingularTable(true)
+			gorm.DefaultTableNameHandler = func(db *gorm.DB, defaultTableName string) string {
+				defaultTableName = strings.TrimSuffix(defaultTableName, "_model")
+				if defaultTableName == "catalog" {
+					return defaultTableName
+				}
+				return "catalog_" + defaultTableName
+			}
+
+			if err := db.AutoMigrate(&CatalogModel{}, &TemplateModel{}, &VersionModel{}).Error; err != nil {
+				t.Fatalf("migrating: %v", err)
+			}
+
+			catalog := CatalogModel{Catalog: Catalog{Name: "library"}}
+			if err := db.Create(&catalog).Error; err != nil {
+				t.Fatalf("creating catalog: %v", err)
+			}
+
+			template := TemplateModel{Template: Template{CatalogId: catalog.ID, FolderName: "my-app"}}
+			if err := db.Create(&template).Error; err != nil {
+				t.Fatalf("creating template: %v", err)
+			}
+
+			globalVersion := VersionModel{Version: Version{
+				TemplateId:    template.ID,
+				Revision:      1,
+				EnvironmentId: "global",
+			}}
+			if err := db.Create(&globalVersion).Error; err != nil {
+				t.Fatalf("creating global version: %v", err)
+			}
+
+			scopedVersion := VersionModel{Version: Version{
+				TemplateId:    template.ID,
+				Revision:      2,
+				EnvironmentId: "env-a",
+			}}
+			if err := db.Create(&scopedVersion).Error; err != nil {
+				t.Fatalf("creating scoped version: %v", err)
+			}
+
+			if got := LookupVersionModel(db, "env-b", "library", "my-app", 1); got.Revision != 1 {
+				t.Errorf("LookupVersionModel(env-b, revision 1) = %+v, want the global version", got)
+			}
+			if got := LookupVersionModel(db, "env-b", "library", "my-app", 2); got.Revision != 0 {
+				t.Errorf("LookupVersionModel(env-b, revision 2) = %+v, want no match for another environment's version", got)
+			}
+			if got := LookupVersionModel(db, "env-a", "library", "my-app", 2); got.Revision != 2 {
+				t.Errorf("LookupVersionModel(env-a, revision 2) = %+v, want the env-a version", got)
+			}
+
+			versions := LookupVersions(db, "env-a", "library", "my-app")
+			if len(versions) != 2 {
+				t.Fatalf("LookupVersions(env-a, ...) returned %d versions, want 2 (global + env-a)", len(versions))
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// MuxWrapper gates Router behind readiness: until IsReady is set, every
+// request other than the health endpoints and /metrics gets a 503, so
+// Kubernetes doesn't route traffic before the initial catalog sync has
+// populated the database. /healthz and /readyz are always served, even
+// while not ready, so operators can tell "process hung" from "still
+// syncing".
+type MuxWrapper struct {
+	Router  http.Handler
+	IsReady bool
+	DB      *gorm.DB
+
+	mu sync.RWMutex
+}
+
+// SetReady flips the readiness gate. It's safe to call concurrently with
+// ServeHTTP.
+func (w *MuxWrapper) SetReady(ready bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.IsReady = ready
+}
+
+func (w *MuxWrapper) ready() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.IsReady
+}
+
+func (w *MuxWrapper) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/healthz":
+		w.serveHealthz(rw)
+		return
+	case "/readyz":
+		w.serveReadyz(rw)
+		return
+	case "/metrics":
+		// Scraping must keep working through the initial sync so
+		// operators have visibility into exactly the slow/huge catalog
+		// case the refresh metrics exist for.
+		w.Router.ServeHTTP(rw, r)
+		return
+	}
+
+	if !w.ready() {
+		http.Error(rw, "Not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.Router.ServeHTTP(rw, r)
+}
+
+// serveHealthz is the liveness probe: OK whenever the process is up and
+// can still reach the database, regardless of initial sync state.
+func (w *MuxWrapper) serveHealthz(rw http.ResponseWriter) {
+	if err := pingDB(w.DB); err != nil {
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+// serveReadyz is the readiness probe: OK only once the initial catalog
+// sync has completed and the database is still reachable.
+func (w *MuxWrapper) serveReadyz(rw http.ResponseWriter) {
+	if !w.ready() {
+		http.Error(rw, "still performing initial catalog sync", http.StatusServiceUnavailable)
+		return
+	}
+	if err := pingDB(w.DB); err != nil {
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+func pingDB(db *gorm.DB) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	return db.DB().PingContext(ctx)
+}
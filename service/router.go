@@ -0,0 +1,33 @@
+// Package service builds the catalog service's HTTP API: the v1-catalog
+// REST endpoints, operational status/refresh/health endpoints, and
+// Prometheus metrics.
+package service
+
+import (
+	"io/fs"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rancher/catalog-service/manager"
+)
+
+// NewRouter builds the HTTP handler for the catalog service, serving
+// bundled assets out of assetsFS.
+func NewRouter(m *manager.Manager, db *gorm.DB, assetsFS fs.FS) *mux.Router {
+	router := mux.NewRouter()
+	router.StrictSlash(true)
+
+	router.Handle("/metrics", promhttp.Handler())
+
+	router.HandleFunc("/v1-catalog/catalogs/{name}/status", catalogStatusHandler(db)).Methods(http.MethodGet)
+	router.HandleFunc("/v1-catalog/catalogs/{name}/refresh", catalogRefreshHandler(m)).Methods(http.MethodPost)
+
+	// assetsFS already has "schemas" at its own root (see the
+	// //go:embed schemas repo.json directive in package assets), so the
+	// request path must be served as-is, not stripped again.
+	router.PathPrefix("/schemas/").Handler(http.FileServer(http.FS(assetsFS)))
+
+	return router
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRouterServesSchemasFromAssetsFS(t *testing.T) {
+	assetsFS := fstest.MapFS{
+		"schemas/questions.schema.json": &fstest.MapFile{Data: []byte(`{"type":"object"}`)},
+	}
+
+	router := NewRouter(nil, nil, assetsFS)
+
+	req := httptest.NewRequest(http.MethodGet, "/schemas/questions.schema.json", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /schemas/questions.schema.json = %d, want %d", rr.Code, http.StatusOK)
+	}
+	if got := rr.Body.String(); got != `{"type":"object"}` {
+		t.Fatalf("unexpected body: %s", got)
+	}
+}
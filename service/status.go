@@ -0,0 +1,63 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/rancher/catalog-service/manager"
+	"github.com/rancher/catalog-service/model"
+)
+
+type catalogStatus struct {
+	Name        string `json:"name"`
+	LastRefresh string `json:"lastRefresh,omitempty"`
+	LastError   string `json:"lastError,omitempty"`
+	DurationMs  int64  `json:"durationMs"`
+}
+
+// catalogStatusHandler reports the last refresh outcome for the catalog
+// named in the request path.
+func catalogStatusHandler(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+
+		var catalog model.CatalogModel
+		if err := db.Where("name = ?", name).First(&catalog).Error; err != nil {
+			http.Error(w, "catalog not found", http.StatusNotFound)
+			return
+		}
+
+		status := catalogStatus{
+			Name:       catalog.Name,
+			LastError:  catalog.LastError,
+			DurationMs: catalog.DurationMs,
+		}
+		if !catalog.LastRefresh.IsZero() {
+			status.LastRefresh = catalog.LastRefresh.Format(time.RFC3339)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	}
+}
+
+// catalogRefreshHandler triggers an immediate, out-of-band refresh of the
+// catalog named in the request path and returns without waiting for it
+// to finish.
+func catalogRefreshHandler(m *manager.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+
+		go func() {
+			if err := m.RefreshCatalog(name); err != nil {
+				log.Errorf("Failed to refresh catalog %q: %v", name, err)
+			}
+		}()
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}